@@ -0,0 +1,190 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Framer turns a byte stream into discrete messages. OpenPipeReader and
+// OpenPipeWriter use a Framer to decide where one message ends and the next
+// begins, so the same pipe code can speak length-prefixed binary framing,
+// newline-delimited text, or netstrings depending on what the peer expects.
+type Framer interface {
+	// ReadFrame reads and returns exactly one message from r.
+	ReadFrame(r *bufio.Reader) ([]byte, error)
+
+	// WriteFrame writes data to w as exactly one message. Callers are
+	// responsible for flushing w afterwards.
+	WriteFrame(w *bufio.Writer, data []byte) error
+}
+
+// Option configures optional behavior of OpenPipeReader and OpenPipeWriter.
+type Option func(*pipeOptions)
+
+type pipeOptions struct {
+	framer        Framer
+	reconnect     ReconnectPolicy
+	highWaterMark int
+}
+
+func defaultPipeOptions() *pipeOptions {
+	return &pipeOptions{
+		framer:    LenPrefixFramer{ByteOrder: binary.LittleEndian, SizeBytes: 8},
+		reconnect: Never,
+	}
+}
+
+// WithFramer selects the Framer used to split the pipe's byte stream into
+// messages. The default is a LenPrefixFramer matching the historical wire
+// format (little-endian uint64 length prefix).
+func WithFramer(f Framer) Option {
+	return func(o *pipeOptions) {
+		o.framer = f
+	}
+}
+
+// LenPrefixFramer frames messages as a fixed-width length prefix followed by
+// that many bytes of payload.
+type LenPrefixFramer struct {
+	// ByteOrder is the encoding used for the length prefix.
+	ByteOrder binary.ByteOrder
+
+	// SizeBytes is the width of the length prefix in bytes: 2, 4, or 8.
+	SizeBytes int
+}
+
+func (f LenPrefixFramer) readSize(r *bufio.Reader) (uint64, error) {
+	sizeBytes := make([]byte, f.SizeBytes)
+	if _, err := io.ReadFull(r, sizeBytes); err != nil {
+		return 0, err
+	}
+
+	switch f.SizeBytes {
+	case 2:
+		return uint64(f.ByteOrder.Uint16(sizeBytes)), nil
+	case 4:
+		return uint64(f.ByteOrder.Uint32(sizeBytes)), nil
+	case 8:
+		return f.ByteOrder.Uint64(sizeBytes), nil
+	default:
+		return 0, errors.New("ipc: LenPrefixFramer.SizeBytes must be 2, 4, or 8")
+	}
+}
+
+func (f LenPrefixFramer) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	size, err := f.readSize(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (f LenPrefixFramer) WriteFrame(w *bufio.Writer, data []byte) error {
+	sizeBytes := make([]byte, f.SizeBytes)
+	switch f.SizeBytes {
+	case 2:
+		f.ByteOrder.PutUint16(sizeBytes, uint16(len(data)))
+	case 4:
+		f.ByteOrder.PutUint32(sizeBytes, uint32(len(data)))
+	case 8:
+		f.ByteOrder.PutUint64(sizeBytes, uint64(len(data)))
+	default:
+		return errors.New("ipc: LenPrefixFramer.SizeBytes must be 2, 4, or 8")
+	}
+
+	if _, err := w.Write(sizeBytes); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// LineFramer frames messages as lines of text separated by Delimiter, as
+// used by newline-delimited JSON peers (common in Elixir/Erlang port-style
+// protocols). The returned message never includes the delimiter.
+type LineFramer struct {
+	// Delimiter separates messages. Typically '\n'.
+	Delimiter byte
+
+	// MaxLine bounds the size of a single message; ReadFrame returns an
+	// error if a message (not counting the delimiter) is longer than
+	// MaxLine. The whole line is read up to the delimiter before its length
+	// is checked. Zero means unbounded.
+	MaxLine int
+}
+
+func (f LineFramer) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes(f.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.MaxLine > 0 && len(line)-1 > f.MaxLine {
+		return nil, errors.New("ipc: LineFramer: line exceeds MaxLine")
+	}
+
+	return line[:len(line)-1], nil
+}
+
+func (f LineFramer) WriteFrame(w *bufio.Writer, data []byte) error {
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return w.WriteByte(f.Delimiter)
+}
+
+// NetstringFramer frames messages as classic netstrings: "<length>:<data>,".
+type NetstringFramer struct{}
+
+func (NetstringFramer) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	lengthStr, err := r.ReadString(':')
+	if err != nil {
+		return nil, err
+	}
+	lengthStr = lengthStr[:len(lengthStr)-1]
+
+	var size uint64
+	for _, c := range []byte(lengthStr) {
+		if c < '0' || c > '9' {
+			return nil, errors.New("ipc: NetstringFramer: malformed length")
+		}
+		size = size*10 + uint64(c-'0')
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	comma, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if comma != ',' {
+		return nil, errors.New("ipc: NetstringFramer: missing trailing comma")
+	}
+
+	return data, nil
+}
+
+func (NetstringFramer) WriteFrame(w *bufio.Writer, data []byte) error {
+	if _, err := io.WriteString(w, strconv.Itoa(len(data))); err != nil {
+		return err
+	}
+	if err := w.WriteByte(':'); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return w.WriteByte(',')
+}
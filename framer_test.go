@@ -0,0 +1,137 @@
+package ipc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func roundTrip(t *testing.T, f Framer, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := f.WriteFrame(w, data); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got, err := f.ReadFrame(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	return got
+}
+
+func TestLenPrefixFramerRoundTrip(t *testing.T) {
+	cases := []struct {
+		name      string
+		sizeBytes int
+		data      []byte
+	}{
+		{"empty", 8, nil},
+		{"2-byte prefix", 2, []byte("hello")},
+		{"4-byte prefix", 4, []byte("hello")},
+		{"8-byte prefix", 8, bytes.Repeat([]byte("x"), 1024)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := LenPrefixFramer{ByteOrder: binary.LittleEndian, SizeBytes: c.sizeBytes}
+			got := roundTrip(t, f, c.data)
+			if !bytes.Equal(got, c.data) {
+				t.Errorf("got %q, want %q", got, c.data)
+			}
+		})
+	}
+}
+
+func TestLenPrefixFramerInvalidSizeBytes(t *testing.T) {
+	f := LenPrefixFramer{ByteOrder: binary.LittleEndian, SizeBytes: 3}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := f.WriteFrame(w, []byte("x")); err == nil {
+		t.Error("WriteFrame: expected error for unsupported SizeBytes, got nil")
+	}
+
+	buf.Write(make([]byte, 3))
+	if _, err := f.ReadFrame(bufio.NewReader(&buf)); err == nil {
+		t.Error("ReadFrame: expected error for unsupported SizeBytes, got nil")
+	}
+}
+
+func TestLineFramerRoundTrip(t *testing.T) {
+	f := LineFramer{Delimiter: '\n'}
+	data := []byte("hello world")
+
+	got := roundTrip(t, f, data)
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+}
+
+func TestLineFramerMaxLine(t *testing.T) {
+	f := LineFramer{Delimiter: '\n', MaxLine: 4}
+
+	r := bufio.NewReader(bytes.NewBufferString("toolong\n"))
+	if _, err := f.ReadFrame(r); err == nil {
+		t.Error("ReadFrame: expected error for line exceeding MaxLine, got nil")
+	}
+}
+
+func TestLineFramerReadFrameMissingDelimiter(t *testing.T) {
+	f := LineFramer{Delimiter: '\n'}
+
+	r := bufio.NewReader(bytes.NewBufferString("no newline"))
+	if _, err := f.ReadFrame(r); err == nil {
+		t.Error("ReadFrame: expected error when delimiter is never found, got nil")
+	}
+}
+
+func TestNetstringFramerRoundTrip(t *testing.T) {
+	cases := [][]byte{
+		nil,
+		[]byte("hello"),
+		bytes.Repeat([]byte("x"), 1024),
+	}
+
+	for _, data := range cases {
+		got := roundTrip(t, NetstringFramer{}, data)
+		if !bytes.Equal(got, data) {
+			t.Errorf("got %q, want %q", got, data)
+		}
+	}
+}
+
+func TestNetstringFramerMalformedLength(t *testing.T) {
+	f := NetstringFramer{}
+
+	r := bufio.NewReader(bytes.NewBufferString("12x:hello,"))
+	if _, err := f.ReadFrame(r); err == nil {
+		t.Error("ReadFrame: expected error for non-digit length, got nil")
+	}
+}
+
+func TestNetstringFramerMissingTrailingComma(t *testing.T) {
+	f := NetstringFramer{}
+
+	r := bufio.NewReader(bytes.NewBufferString("5:hello."))
+	if _, err := f.ReadFrame(r); err == nil {
+		t.Error("ReadFrame: expected error for missing trailing comma, got nil")
+	}
+}
+
+func TestNetstringFramerTruncatedData(t *testing.T) {
+	f := NetstringFramer{}
+
+	r := bufio.NewReader(bytes.NewBufferString("5:hi"))
+	if _, err := f.ReadFrame(r); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadFrame: got err %v, want io.ErrUnexpectedEOF", err)
+	}
+}
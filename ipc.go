@@ -2,57 +2,65 @@ package ipc
 
 import (
 	"bufio"
-	"encoding/binary"
 	"errors"
-	"io"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
 	"github.com/golang/glog"
 )
 
-// Creates a pipe at pipePath, deletes a previous file with the same name if it exists
-func CreatePipe(pipePath string) error {
-	if doesFileExist(pipePath) {
-		err := os.Remove(pipePath)
-		if err != nil {
-			return err
-		}
-	}
-
-	return syscall.Mkfifo(pipePath, 0777)
-}
-
 // Blocking call to output the data pipePath into pipeData
 // Reads data from the pipe in format [size uint64, bytes []byte] where len(bytes) == size and (pipeData <- bytes)
-// All data is in little endian format
-func OpenPipeReader(pipePath string, pipeData chan<- []byte) error {
+// All data is in little endian format by default; pass WithFramer to use a different wire format, and
+// WithReconnectPolicy to reopen the pipe instead of returning when the peer disconnects
+func OpenPipeReader(pipePath string, pipeData chan<- []byte, opts ...Option) error {
 	if !doesFileExist(pipePath) {
 		return errors.New("File doesn't exitst")
 	}
 
+	options := defaultPipeOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	pipeChannel := make(chan []byte, 10)
 
 	go func(pipeChannel chan<- []byte) {
-		setupCloseHandler()
-
-		pipe, fileErr := os.OpenFile(pipePath, os.O_RDONLY, 0777)
+		pipe, fileErr := defaultTransport.OpenReader(pipePath)
 		if fileErr != nil {
 			glog.Error("Cannot open pipe for reading:", fileErr)
+			return
 		}
-		defer pipe.Close()
-
 		reader := bufio.NewReader(pipe)
 
+		attempt := 0
 		for {
-			const numSizeBytes = 64 / 8
-
-			readSizeBytes := loggedRead(reader, numSizeBytes)
-			readSize := binary.LittleEndian.Uint64(readSizeBytes[:])
-
-			readData := loggedRead(reader, readSize)
-
+			readData, frameErr := options.framer.ReadFrame(reader)
+			if frameErr != nil {
+				pipe.Close()
+
+				attempt++
+				delay, retry := options.reconnect.next(attempt)
+				if !retry {
+					glog.Error("Pipe Reading Error: ", frameErr)
+					return
+				}
+
+				if delay > 0 {
+					time.Sleep(delay)
+				}
+
+				pipe, fileErr = defaultTransport.OpenReader(pipePath)
+				if fileErr != nil {
+					glog.Error("Cannot reopen pipe for reading:", fileErr)
+					return
+				}
+				reader = bufio.NewReader(pipe)
+
+				continue
+			}
+
+			attempt = 0
 			pipeChannel <- readData
 		}
 
@@ -63,70 +71,77 @@ func OpenPipeReader(pipePath string, pipeData chan<- []byte) error {
 
 // Blocking call that will continously write the data pipeInput into pipePath
 // Byte strings will be written as [size uint64, bytes []byte] where len(bytes) == size and (bytes := <-pipeInput)
-// All data is in little endian format
-func OpenPipeWriter(pipePath string, pipeInput <-chan []byte) error {
+// All data is in little endian format by default; pass WithFramer to use a different wire format, and
+// WithReconnectPolicy to reopen the pipe instead of returning when the peer disconnects; while it reconnects,
+// no further messages are read from pipeInput (see WithHighWaterMark)
+func OpenPipeWriter(pipePath string, pipeInput <-chan []byte, opts ...Option) error {
 	if !doesFileExist(pipePath) {
 		return errors.New("File doesn't exitst")
 	}
 
-	go func(pipeChannel <-chan []byte) {
-		setupCloseHandler()
+	options := defaultPipeOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
 
-		pipe, fileErr := os.OpenFile(pipePath, os.O_WRONLY, 0777)
+	go func(pipeChannel <-chan []byte) {
+		pipe, fileErr := defaultTransport.OpenWriter(pipePath)
 		if fileErr != nil {
 			glog.Error("Cannot open pipe for writing:", fileErr)
+			return
 		}
-		defer pipe.Close()
-
 		writer := bufio.NewWriter(pipe)
 
-		for data := range pipeInput {
-			var writeSizeBytes [8]byte
-			binary.LittleEndian.PutUint64(writeSizeBytes[:], uint64(len(data)))
-
-			loggedWrite(writer, writeSizeBytes[:])
-			loggedWrite(writer, data)
-			writer.Flush()
+		var buffered [][]byte
+		attempt := 0
+
+		for data := range pipeChannel {
+			buffered = append(buffered, data)
+			if options.highWaterMark > 0 && len(buffered) > options.highWaterMark {
+				glog.Error("Pipe Writing Error: high-water mark exceeded, dropping oldest buffered message")
+				buffered = buffered[1:]
+			}
+
+			for len(buffered) > 0 {
+				frameErr := options.framer.WriteFrame(writer, buffered[0])
+				if frameErr == nil {
+					frameErr = writer.Flush()
+				}
+				if frameErr == nil {
+					buffered = buffered[1:]
+					attempt = 0
+					continue
+				}
+
+				pipe.Close()
+
+				attempt++
+				delay, retry := options.reconnect.next(attempt)
+				if !retry {
+					glog.Error("Pipe Writing Error: ", frameErr)
+					return
+				}
+
+				if delay > 0 {
+					time.Sleep(delay)
+				}
+
+				pipe, fileErr = defaultTransport.OpenWriter(pipePath)
+				if fileErr != nil {
+					glog.Error("Cannot reopen pipe for writing:", fileErr)
+					return
+				}
+				writer = bufio.NewWriter(pipe)
+			}
 		}
 
+		pipe.Close()
+
 	}(pipeInput)
 
 	return nil
 }
 
-func loggedRead(reader io.Reader, numBytes uint64) []byte {
-	readData := make([]byte, numBytes)
-	bytesRead, readErr := io.ReadFull(reader, readData)
-
-	if readErr != nil {
-		glog.Error("Pipe Writing Error: ", readErr, "[Desired Write size = ", numBytes, " Actually written size = ", bytesRead, "]")
-		return nil
-	} else {
-		return readData
-	}
-}
-
-func loggedWrite(writer io.Writer, data []byte) {
-	bytesWritten, writeErr := writer.Write(data)
-
-	if writeErr != nil {
-		os.Exit(1)
-		glog.Error("Pipe Writing Error: ", writeErr, "[Desired Write size = ", len(data), " Actually written size = ", bytesWritten, "]")
-	}
-}
-
-// SetupCloseHandler creates a 'listener' on a new goroutine which will notify the
-// program if it receives an interrupt from the OS. We then handle this by calling
-// our clean up procedure and exiting the program.
-func setupCloseHandler() {
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		os.Exit(0)
-	}()
-}
-
 func doesFileExist(fileName string) bool {
 	_, error := os.Stat(fileName)
 
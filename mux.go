@@ -0,0 +1,170 @@
+package ipc
+
+import "sync"
+
+// MuxWriter multiplexes several logical streams over a single underlying
+// Writer by tagging each frame with a one-byte stream_id before the payload.
+// This lets one pipe pair carry, e.g., stdout, stderr, control messages, and
+// heartbeats without opening extra FIFOs.
+type MuxWriter struct {
+	mu      sync.Mutex
+	writer  *Writer
+	streams map[uint8]*MuxStreamWriter
+}
+
+// NewMuxWriter wraps writer so callers address individual streams via
+// Stream instead of writing raw tagged frames themselves.
+func NewMuxWriter(writer *Writer) *MuxWriter {
+	return &MuxWriter{writer: writer, streams: make(map[uint8]*MuxStreamWriter)}
+}
+
+// Stream returns the MuxStreamWriter for id, creating it on first use.
+func (m *MuxWriter) Stream(id uint8) *MuxStreamWriter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.streams[id]
+	if !ok {
+		s = &MuxStreamWriter{id: id, mux: m}
+		m.streams[id] = s
+	}
+
+	return s
+}
+
+// Close closes the underlying Writer.
+func (m *MuxWriter) Close() error {
+	return m.writer.Close()
+}
+
+// MuxStreamWriter is a single logical stream of a MuxWriter.
+type MuxStreamWriter struct {
+	id  uint8
+	mux *MuxWriter
+}
+
+// Send writes data as one frame tagged with this stream's id. Concurrent
+// Sends across streams of the same MuxWriter are serialized so frames are
+// never interleaved on the wire.
+func (s *MuxStreamWriter) Send(data []byte) error {
+	s.mux.mu.Lock()
+	defer s.mux.mu.Unlock()
+
+	frame := make([]byte, 1+len(data))
+	frame[0] = s.id
+	copy(frame[1:], data)
+
+	return s.mux.writer.Send(frame)
+}
+
+// MuxReader demultiplexes frames read from a single Reader into per-stream
+// channels keyed by the leading stream_id byte of each frame.
+type MuxReader struct {
+	reader     *Reader
+	bufferSize int
+
+	mu      sync.Mutex
+	streams map[uint8]chan []byte
+
+	errOnce sync.Once
+	errCh   chan struct{}
+	err     error
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewMuxReader wraps reader and immediately starts demultiplexing frames on
+// a background goroutine. bufferSize sets the channel capacity given to
+// each stream created via Stream; once a stream's channel is full, the
+// demux goroutine blocks delivering to it, applying backpressure to every
+// other stream until that consumer catches up.
+func NewMuxReader(reader *Reader, bufferSize int) *MuxReader {
+	m := &MuxReader{
+		reader:     reader,
+		bufferSize: bufferSize,
+		streams:    make(map[uint8]chan []byte),
+		errCh:      make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go m.demux()
+
+	return m
+}
+
+// Stream returns the receive channel for id, creating it (and its buffer)
+// on first use. The channel is closed once the underlying Reader returns an
+// error (commonly io.EOF when the peer disconnects).
+func (m *MuxReader) Stream(id uint8) <-chan []byte {
+	return m.streamChan(id)
+}
+
+func (m *MuxReader) streamChan(id uint8) chan []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch, ok := m.streams[id]
+	if !ok {
+		ch = make(chan []byte, m.bufferSize)
+		m.streams[id] = ch
+	}
+
+	return ch
+}
+
+func (m *MuxReader) demux() {
+	defer func() {
+		m.mu.Lock()
+		for _, ch := range m.streams {
+			close(ch)
+		}
+		m.mu.Unlock()
+	}()
+
+	for {
+		frame, err := m.reader.Recv()
+		if err != nil {
+			m.errOnce.Do(func() {
+				m.err = err
+				close(m.errCh)
+			})
+			return
+		}
+
+		if len(frame) == 0 {
+			continue
+		}
+
+		id := frame[0]
+		select {
+		case m.streamChan(id) <- frame[1:]:
+		case <-m.done:
+			// Close was called while we were blocked applying backpressure
+			// to this stream's consumer. Abandon this frame and exit rather
+			// than wait for a consumer that may never come back.
+			return
+		}
+	}
+}
+
+// Err returns the error that stopped demultiplexing, or nil while
+// demultiplexing is still running.
+func (m *MuxReader) Err() error {
+	select {
+	case <-m.errCh:
+		return m.err
+	default:
+		return nil
+	}
+}
+
+// Close unblocks the demux goroutine and closes every stream channel, then
+// closes the underlying Reader. Closing the Reader alone unblocks demux when
+// it's parked reading the next frame, but not when it's parked delivering a
+// frame to a stream channel that's full and not being drained; done covers
+// that second case.
+func (m *MuxReader) Close() error {
+	m.closeOnce.Do(func() { close(m.done) })
+	return m.reader.Close()
+}
@@ -0,0 +1,152 @@
+package ipc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+// rawFrame returns the length-prefixed encoding of a single mux frame: a
+// leading stream_id byte followed by payload, matching what MuxReader.demux
+// expects to read via Reader.Recv.
+func rawFrame(streamID byte, payload string) []byte {
+	data := append([]byte{streamID}, payload...)
+
+	var sizeBytes [8]byte
+	binary.LittleEndian.PutUint64(sizeBytes[:], uint64(len(data)))
+
+	return append(sizeBytes[:], data...)
+}
+
+// newTestMuxReader builds a MuxReader reading from a fixed, already-complete
+// byte stream, so demux's behavior is driven entirely by channel buffering
+// and draining rather than by timing of writes to the underlying pipe.
+func newTestMuxReader(frames []byte, bufferSize int) *MuxReader {
+	r := &Reader{
+		ctx:    context.Background(),
+		cancel: func() {},
+		file:   io.NopCloser(bytes.NewReader(nil)),
+		reader: bufio.NewReader(bytes.NewReader(frames)),
+	}
+	return NewMuxReader(r, bufferSize)
+}
+
+func TestMuxReaderDemuxTagging(t *testing.T) {
+	frames := append(rawFrame(1, "a"), rawFrame(2, "b")...)
+	mr := newTestMuxReader(frames, 4)
+
+	ch1 := mr.Stream(1)
+	ch2 := mr.Stream(2)
+
+	select {
+	case got := <-ch1:
+		if string(got) != "a" {
+			t.Errorf("stream 1: got %q, want %q", got, "a")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream 1: timed out waiting for frame")
+	}
+
+	select {
+	case got := <-ch2:
+		if string(got) != "b" {
+			t.Errorf("stream 2: got %q, want %q", got, "b")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream 2: timed out waiting for frame")
+	}
+}
+
+func TestMuxReaderDemuxBackpressure(t *testing.T) {
+	frames := append(append(rawFrame(1, "a1"), rawFrame(1, "a2")...), rawFrame(2, "b1")...)
+	mr := newTestMuxReader(frames, 1)
+
+	ch1 := mr.Stream(1)
+	ch2 := mr.Stream(2)
+
+	// Give demux time to deliver "a1" into the size-1 buffer and then block
+	// trying to deliver "a2" into that same full buffer. While it's blocked
+	// there, it cannot have reached the frame for stream 2 at all.
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case got := <-ch2:
+		t.Fatalf("stream 2: received %q before stream 1 was drained", got)
+	default:
+	}
+
+	if got := <-ch1; string(got) != "a1" {
+		t.Fatalf("stream 1: got %q, want %q", got, "a1")
+	}
+	if got := <-ch1; string(got) != "a2" {
+		t.Fatalf("stream 1: got %q, want %q", got, "a2")
+	}
+
+	select {
+	case got := <-ch2:
+		if string(got) != "b1" {
+			t.Errorf("stream 2: got %q, want %q", got, "b1")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream 2: timed out waiting for frame after stream 1 drained")
+	}
+}
+
+func TestMuxReaderDemuxClosesOnError(t *testing.T) {
+	mr := newTestMuxReader(rawFrame(1, "x"), 4)
+	ch1 := mr.Stream(1)
+
+	if got := <-ch1; string(got) != "x" {
+		t.Fatalf("stream 1: got %q, want %q", got, "x")
+	}
+
+	select {
+	case <-mr.errCh:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for demux to stop after EOF")
+	}
+	if mr.Err() != io.EOF {
+		t.Errorf("Err() = %v, want io.EOF", mr.Err())
+	}
+
+	select {
+	case got, ok := <-ch1:
+		if ok {
+			t.Errorf("stream 1: expected channel to be closed, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for stream channel to close")
+	}
+}
+
+func TestMuxReaderCloseUnblocksBackpressuredDemux(t *testing.T) {
+	frames := append(rawFrame(1, "a1"), rawFrame(1, "a2")...)
+	mr := newTestMuxReader(frames, 1)
+
+	ch1 := mr.Stream(1)
+	// Don't drain ch1: once demux delivers "a1" it fills the size-1 buffer,
+	// then blocks trying to deliver "a2" into that same full buffer.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := mr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// demux should abandon the blocked "a2" delivery and exit, closing ch1,
+	// instead of leaking the goroutine forever.
+	if got := <-ch1; string(got) != "a1" {
+		t.Fatalf("stream 1: got %q, want %q", got, "a1")
+	}
+	select {
+	case got, ok := <-ch1:
+		if ok {
+			t.Errorf("stream 1: expected channel to be closed after Close, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for demux to exit after Close")
+	}
+}
@@ -0,0 +1,92 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Reader is a cancellable, error-propagating FIFO reader returned by
+// NewReader. Unlike OpenPipeReader, which logs errors to glog from a
+// background goroutine, it returns every error to the caller from Recv;
+// neither installs a signal handler, so callers stay in control of their own
+// process lifecycle.
+type Reader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	file   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// NewReader opens pipePath for reading using the platform default Transport
+// and returns a Reader. Cancelling ctx while NewReader is blocked in the
+// initial open makes it return ctx.Err(); cancelling it later unblocks a
+// pending Recv and makes it return ctx.Err().
+func NewReader(ctx context.Context, pipePath string) (*Reader, error) {
+	if !doesFileExist(pipePath) {
+		return nil, fmt.Errorf("ipc: pipe %q does not exist", pipePath)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// OpenReaderContext (rather than plain OpenReader) is what makes
+	// cancellation real instead of simulated: each Transport knows how to
+	// unblock its own platform-specific open, so a pending open that never
+	// finds a peer doesn't leak a goroutine (and its pinned OS thread) for
+	// the life of the process.
+	file, err := defaultTransport.OpenReaderContext(ctx, pipePath)
+	if err != nil {
+		cancel()
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ipc: opening %q for reading: %w", pipePath, err)
+	}
+
+	r := &Reader{ctx: ctx, cancel: cancel, file: file, reader: bufio.NewReader(file)}
+
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	return r, nil
+}
+
+// Recv reads the next length-prefixed frame from the pipe, blocking until a
+// full frame is available, ctx passed to NewReader is cancelled, or the
+// peer closes its end (io.EOF).
+func (r *Reader) Recv() ([]byte, error) {
+	const numSizeBytes = 64 / 8
+
+	sizeBytes := make([]byte, numSizeBytes)
+	if _, err := io.ReadFull(r.reader, sizeBytes); err != nil {
+		return nil, r.translateErr(err)
+	}
+	size := binary.LittleEndian.Uint64(sizeBytes)
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.reader, data); err != nil {
+		return nil, r.translateErr(err)
+	}
+
+	return data, nil
+}
+
+// translateErr reports ctx.Err() instead of the underlying "use of closed
+// file" error when a pending read was interrupted by cancellation.
+func (r *Reader) translateErr(err error) error {
+	if err != nil && err != io.EOF && r.ctx.Err() != nil {
+		return r.ctx.Err()
+	}
+	return err
+}
+
+// Close cancels any pending Recv and releases the underlying pipe.
+func (r *Reader) Close() error {
+	r.cancel()
+	return r.file.Close()
+}
@@ -0,0 +1,82 @@
+package ipc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewReaderCancelBeforePeer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p")
+	if err := CreatePipe(path); err != nil {
+		t.Fatalf("CreatePipe: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := NewReader(ctx, path)
+		errCh <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("NewReader: got err %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("NewReader did not return after ctx was cancelled")
+	}
+}
+
+func TestNewReaderConnectsToPeer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p")
+	if err := CreatePipe(path); err != nil {
+		t.Fatalf("CreatePipe: %v", err)
+	}
+
+	ctx := context.Background()
+
+	type result struct {
+		r   *Reader
+		err error
+	}
+	readerCh := make(chan result, 1)
+	go func() {
+		r, err := NewReader(ctx, path)
+		readerCh <- result{r, err}
+	}()
+
+	w, err := NewWriter(ctx, path)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	defer w.Close()
+
+	var res result
+	select {
+	case res = <-readerCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("NewReader did not connect to its peer")
+	}
+	if res.err != nil {
+		t.Fatalf("NewReader: %v", res.err)
+	}
+	defer res.r.Close()
+
+	if err := w.Send([]byte("hello")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	got, err := res.r.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("Recv: got %q, want %q", got, "hello")
+	}
+}
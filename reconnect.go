@@ -0,0 +1,85 @@
+package ipc
+
+import (
+	"context"
+	"time"
+)
+
+// ReconnectPolicy controls whether and how long OpenPipeReader/
+// OpenPipeWriter wait before reopening their pipe after the peer
+// disconnects (EOF on read, EPIPE on write).
+type ReconnectPolicy interface {
+	// next reports the delay before reconnect attempt number attempt (1 for
+	// the first attempt after a disconnect), and whether to attempt it at
+	// all.
+	next(attempt int) (delay time.Duration, retry bool)
+}
+
+// WithReconnectPolicy makes OpenPipeReader/OpenPipeWriter reopen their pipe
+// on disconnect according to policy, instead of returning for good. The
+// default policy is Never.
+func WithReconnectPolicy(policy ReconnectPolicy) Option {
+	return func(o *pipeOptions) {
+		o.reconnect = policy
+	}
+}
+
+// WithHighWaterMark bounds how many outbound messages OpenPipeWriter holds in
+// its internal buffer: when a new message would take the buffer past
+// highWaterMark messages, the oldest buffered message is dropped. Zero (the
+// default) means unbounded. OpenPipeWriter takes the next message from its
+// input channel only after the previous one has been written, reconnecting
+// as its ReconnectPolicy allows, so messages sent during an outage wait in
+// that channel rather than in the buffer. The buffer therefore never holds
+// more than one message, and a highWaterMark of 1 or more drops nothing.
+func WithHighWaterMark(highWaterMark int) Option {
+	return func(o *pipeOptions) {
+		o.highWaterMark = highWaterMark
+	}
+}
+
+type neverReconnect struct{}
+
+func (neverReconnect) next(int) (time.Duration, bool) { return 0, false }
+
+// Never disables reconnection: a disconnect ends OpenPipeReader/
+// OpenPipeWriter for good. This is the default.
+var Never ReconnectPolicy = neverReconnect{}
+
+type alwaysReconnect struct{}
+
+func (alwaysReconnect) next(int) (time.Duration, bool) { return 0, true }
+
+// Always retries immediately and indefinitely.
+var Always ReconnectPolicy = alwaysReconnect{}
+
+// ExponentialBackoff retries indefinitely, with the delay starting at
+// Initial and growing by Factor on each successive attempt up to Max.
+type ExponentialBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+func (b ExponentialBackoff) next(attempt int) (time.Duration, bool) {
+	delay := b.Initial
+	for i := 1; i < attempt; i++ {
+		delay = time.Duration(float64(delay) * b.Factor)
+		if delay >= b.Max {
+			delay = b.Max
+			break
+		}
+	}
+
+	return delay, true
+}
+
+// WaitForPeer blocks until a peer is connected to the pipe at pipePath, or
+// until ctx is cancelled. It uses the platform default Transport, so
+// daemon-style callers don't deadlock on startup ordering while waiting to
+// find out whether their peer is up yet. On Unix the peer it detects is a
+// reader: it returns once any process has the FIFO open for reading, and a
+// writer waiting to open the FIFO is not detected.
+func WaitForPeer(ctx context.Context, pipePath string) error {
+	return defaultTransport.WaitForPeer(ctx, pipePath)
+}
@@ -0,0 +1,49 @@
+package ipc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoffNext(t *testing.T) {
+	b := ExponentialBackoff{
+		Initial: 100 * time.Millisecond,
+		Max:     1 * time.Second,
+		Factor:  2,
+	}
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, 1 * time.Second},  // would be 1.6s uncapped, clamped to Max
+		{6, 1 * time.Second},  // stays at Max
+		{20, 1 * time.Second}, // stays at Max indefinitely
+	}
+
+	for _, c := range cases {
+		delay, retry := b.next(c.attempt)
+		if !retry {
+			t.Errorf("attempt %d: retry = false, want true", c.attempt)
+		}
+		if delay != c.want {
+			t.Errorf("attempt %d: delay = %v, want %v", c.attempt, delay, c.want)
+		}
+	}
+}
+
+func TestNeverReconnect(t *testing.T) {
+	if delay, retry := Never.next(1); retry || delay != 0 {
+		t.Errorf("Never.next(1) = (%v, %v), want (0, false)", delay, retry)
+	}
+}
+
+func TestAlwaysReconnect(t *testing.T) {
+	if delay, retry := Always.next(1); !retry || delay != 0 {
+		t.Errorf("Always.next(1) = (%v, %v), want (0, true)", delay, retry)
+	}
+}
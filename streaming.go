@@ -0,0 +1,127 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Pipe opens pipePath for both reading and writing using the platform
+// default Transport and returns plain io.ReadCloser/io.WriteCloser handles.
+// Unlike NewReader/NewWriter it does no framing at all, so callers can
+// io.Copy large payloads (multi-GB file transfers) straight through without
+// materializing a whole message as a []byte first.
+func Pipe(pipePath string) (io.ReadCloser, io.WriteCloser, error) {
+	if !doesFileExist(pipePath) {
+		return nil, nil, errors.New("File doesn't exitst")
+	}
+
+	type readResult struct {
+		rc  io.ReadCloser
+		err error
+	}
+	type writeResult struct {
+		wc  io.WriteCloser
+		err error
+	}
+
+	rCh := make(chan readResult, 1)
+	wCh := make(chan writeResult, 1)
+
+	// Opening a FIFO for read-only or write-only blocks until the other end
+	// is opened, so we race the two opens against each other on goroutines
+	// instead of doing them sequentially.
+	go func() {
+		rc, err := defaultTransport.OpenReader(pipePath)
+		rCh <- readResult{rc, err}
+	}()
+	go func() {
+		wc, err := defaultTransport.OpenWriter(pipePath)
+		wCh <- writeResult{wc, err}
+	}()
+
+	r, w := <-rCh, <-wCh
+
+	if r.err != nil {
+		if w.wc != nil {
+			w.wc.Close()
+		}
+		return nil, nil, fmt.Errorf("ipc: opening %q for reading: %w", pipePath, r.err)
+	}
+	if w.err != nil {
+		r.rc.Close()
+		return nil, nil, fmt.Errorf("ipc: opening %q for writing: %w", pipePath, w.err)
+	}
+
+	return r.rc, w.wc, nil
+}
+
+// FrameReader exposes length-prefixed frames as bounded io.Readers instead
+// of materialized []byte slices, so a caller streaming a large payload can
+// io.Copy it straight to its destination without an intermediate
+// allocation per frame.
+type FrameReader struct {
+	r       *bufio.Reader
+	pending *io.LimitedReader
+}
+
+// NewFrameReader wraps r for streaming frame reads.
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{r: bufio.NewReader(r)}
+}
+
+// NextFrame reads the length prefix of the next frame and returns its size
+// along with a bounded io.Reader over its body. Any unread bytes from the
+// previous frame's body are discarded first, so callers don't have to
+// fully drain one frame before asking for the next.
+func (f *FrameReader) NextFrame() (uint64, io.Reader, error) {
+	if f.pending != nil && f.pending.N > 0 {
+		if _, err := io.Copy(io.Discard, f.pending); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	var sizeBytes [8]byte
+	if _, err := io.ReadFull(f.r, sizeBytes[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.LittleEndian.Uint64(sizeBytes[:])
+
+	body := &io.LimitedReader{R: f.r, N: int64(size)}
+	f.pending = body
+
+	return size, body, nil
+}
+
+// FrameWriter writes length-prefixed frames whose body comes from an
+// io.Reader, so a caller streaming a large payload never has to hold it in
+// memory as a single []byte.
+type FrameWriter struct {
+	w *bufio.Writer
+}
+
+// NewFrameWriter wraps w for streaming frame writes.
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteFrame writes the length prefix for a frame of size bytes, then
+// copies exactly size bytes from body, mirroring bufio.Writer.ReadFrom's
+// fast path for io.Reader sources that already know their own length (a
+// file, a bytes.Reader, another FrameReader's frame body).
+func (f *FrameWriter) WriteFrame(size uint64, body io.Reader) error {
+	var sizeBytes [8]byte
+	binary.LittleEndian.PutUint64(sizeBytes[:], size)
+
+	if _, err := f.w.Write(sizeBytes[:]); err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(f.w, body, int64(size)); err != nil {
+		return err
+	}
+
+	return f.w.Flush()
+}
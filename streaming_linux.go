@@ -0,0 +1,33 @@
+//go:build linux
+
+package ipc
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// SpliceFrame copies up to n bytes directly from src to dst using the Linux
+// splice(2) syscall, avoiding a userspace copy when forwarding a frame body
+// between two FIFO file descriptors (e.g. proxying one pipe's output into
+// another). Both src and dst must be pipe *os.Files; callers that can't
+// guarantee that should fall back to io.CopyN via a FrameReader/FrameWriter
+// pair instead.
+func SpliceFrame(dst, src *os.File, n int64) (int64, error) {
+	var written int64
+
+	for written < n {
+		nw, err := unix.Splice(int(src.Fd()), nil, int(dst.Fd()), nil, int(n-written), 0)
+		if err != nil {
+			return written, fmt.Errorf("ipc: splice: %w", err)
+		}
+		if nw == 0 {
+			break
+		}
+		written += nw
+	}
+
+	return written, nil
+}
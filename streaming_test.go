@@ -0,0 +1,143 @@
+package ipc
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFrameReaderWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf)
+
+	if err := fw.WriteFrame(5, bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+	if err := fw.WriteFrame(3, bytes.NewReader([]byte("bye"))); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+
+	fr := NewFrameReader(&buf)
+
+	size, body, err := fr.NextFrame()
+	if err != nil {
+		t.Fatalf("NextFrame: %v", err)
+	}
+	if size != 5 {
+		t.Errorf("NextFrame: size = %d, want 5", size)
+	}
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading first frame body: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("first frame body = %q, want %q", got, "hello")
+	}
+
+	size, body, err = fr.NextFrame()
+	if err != nil {
+		t.Fatalf("NextFrame: %v", err)
+	}
+	if size != 3 {
+		t.Errorf("NextFrame: size = %d, want 3", size)
+	}
+	got, err = io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading second frame body: %v", err)
+	}
+	if string(got) != "bye" {
+		t.Errorf("second frame body = %q, want %q", got, "bye")
+	}
+}
+
+func TestFrameReaderDiscardsUnreadBody(t *testing.T) {
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf)
+	if err := fw.WriteFrame(5, bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+	if err := fw.WriteFrame(3, bytes.NewReader([]byte("bye"))); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+
+	fr := NewFrameReader(&buf)
+
+	_, body, err := fr.NextFrame()
+	if err != nil {
+		t.Fatalf("NextFrame: %v", err)
+	}
+	partial := make([]byte, 2)
+	if _, err := io.ReadFull(body, partial); err != nil {
+		t.Fatalf("reading partial first frame body: %v", err)
+	}
+	if string(partial) != "he" {
+		t.Errorf("partial first frame body = %q, want %q", partial, "he")
+	}
+
+	// The rest of "hello" was never read; NextFrame must discard it before
+	// reading the next length prefix, rather than handing back "llo" as if
+	// it were part of the second frame.
+	size, body, err := fr.NextFrame()
+	if err != nil {
+		t.Fatalf("NextFrame: %v", err)
+	}
+	if size != 3 {
+		t.Errorf("NextFrame: size = %d, want 3", size)
+	}
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading second frame body: %v", err)
+	}
+	if string(got) != "bye" {
+		t.Errorf("second frame body = %q, want %q", got, "bye")
+	}
+}
+
+func TestPipeRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p")
+	if err := CreatePipe(path); err != nil {
+		t.Fatalf("CreatePipe: %v", err)
+	}
+
+	type result struct {
+		r   io.ReadCloser
+		w   io.WriteCloser
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		r, w, err := Pipe(path)
+		ch <- result{r, w, err}
+	}()
+
+	var res result
+	select {
+	case res = <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Pipe did not return")
+	}
+	if res.err != nil {
+		t.Fatalf("Pipe: %v", res.err)
+	}
+	defer res.r.Close()
+	defer res.w.Close()
+
+	writeErrCh := make(chan error, 1)
+	go func() {
+		_, err := res.w.Write([]byte("streamed"))
+		writeErrCh <- err
+	}()
+
+	got := make([]byte, len("streamed"))
+	if _, err := io.ReadFull(res.r, got); err != nil {
+		t.Fatalf("reading from Pipe: %v", err)
+	}
+	if string(got) != "streamed" {
+		t.Errorf("got %q, want %q", got, "streamed")
+	}
+	if err := <-writeErrCh; err != nil {
+		t.Fatalf("writing to Pipe: %v", err)
+	}
+}
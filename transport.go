@@ -0,0 +1,44 @@
+package ipc
+
+import (
+	"context"
+	"io"
+)
+
+// Transport abstracts the platform-specific mechanics of creating and opening
+// a named pipe so the framing and reader/writer code above can stay portable.
+// CreatePipe/OpenPipeReader/OpenPipeWriter use defaultTransport, which is
+// selected per-platform by the build-tagged files in this package.
+type Transport interface {
+	// Create makes a new pipe at path, removing any existing file at that
+	// path first. It must be safe to call before either end is opened.
+	Create(path string) error
+
+	// OpenReader blocks until the pipe at path is ready for reading and
+	// returns the read end.
+	OpenReader(path string) (io.ReadCloser, error)
+
+	// OpenWriter blocks until the pipe at path is ready for writing and
+	// returns the write end.
+	OpenWriter(path string) (io.WriteCloser, error)
+
+	// OpenReaderContext is OpenReader, but cancellable: each implementation
+	// is responsible for unblocking its own platform-specific open when ctx
+	// is cancelled (rather than leaving that to a caller that can't know
+	// how), returning ctx.Err() once it does. NewReader uses this instead of
+	// OpenReader so a pending open that never finds a peer doesn't leak a
+	// goroutine (and its pinned OS thread) for the life of the process.
+	OpenReaderContext(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// WaitForPeer blocks until a peer is connected to the pipe at path, or
+	// until ctx is cancelled. The Unix transport only probes the FIFO and
+	// detects a reader; the Windows transport establishes the pipe's shared
+	// connection, which later OpenReader/OpenWriter calls return.
+	WaitForPeer(ctx context.Context, path string) error
+}
+
+// CreatePipe creates a pipe at pipePath using the platform default Transport,
+// deleting a previous file with the same name if it exists.
+func CreatePipe(pipePath string) error {
+	return defaultTransport.Create(pipePath)
+}
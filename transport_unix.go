@@ -0,0 +1,104 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// defaultTransport is the Transport used by CreatePipe/OpenPipeReader/
+// OpenPipeWriter on non-Windows platforms.
+var defaultTransport Transport = unixTransport{}
+
+// unixTransport implements Transport on top of a POSIX FIFO created with
+// syscall.Mkfifo.
+type unixTransport struct{}
+
+func (unixTransport) Create(path string) error {
+	if doesFileExist(path) {
+		err := os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return syscall.Mkfifo(path, 0777)
+}
+
+func (unixTransport) OpenReader(path string) (io.ReadCloser, error) {
+	return os.OpenFile(path, os.O_RDONLY, 0777)
+}
+
+func (unixTransport) OpenWriter(path string) (io.WriteCloser, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0777)
+}
+
+// OpenReaderContext is OpenReader, but cancellable. The blocking open runs
+// on its own goroutine; if ctx is cancelled before it completes, we wake it
+// by opening path O_WRONLY ourselves, since a FIFO open() increments its
+// reader/writer count on entry, before waiting on the complementary count —
+// so our throwaway writer rendezvous with the still-blocked O_RDONLY open
+// and completes it the same way a real writer would. We then discard
+// whatever that produced and return ctx.Err().
+func (unixTransport) OpenReaderContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	opened := make(chan struct{})
+	var file io.ReadCloser
+	var err error
+	go func() {
+		file, err = os.OpenFile(path, os.O_RDONLY, 0777)
+		close(opened)
+	}()
+
+	select {
+	case <-opened:
+		return file, err
+	case <-ctx.Done():
+		if peer, werr := os.OpenFile(path, os.O_WRONLY, 0777); werr == nil {
+			peer.Close()
+		}
+		<-opened
+		if err == nil {
+			file.Close()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// WaitForPeer detects a reader attaching to the FIFO at path by repeatedly
+// opening it O_WRONLY|O_NONBLOCK: per open(2), that open fails with ENXIO
+// as long as no process has the FIFO open for reading, and succeeds
+// immediately once one does. (A plain O_RDWR|O_NONBLOCK open always
+// succeeds on a FIFO regardless of whether a peer is present, since it
+// makes the caller its own reader too, so it can't be used to detect one.)
+// Each probe fd is closed right away; the real OpenWriter that follows
+// opens its own.
+func (unixTransport) WaitForPeer(ctx context.Context, path string) error {
+	const pollInterval = 50 * time.Millisecond
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0777)
+		if err == nil {
+			file.Close()
+			return nil
+		}
+		if !errors.Is(err, syscall.ENXIO) {
+			return fmt.Errorf("ipc: opening %q to wait for peer: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//go:build windows
+
+package ipc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+// defaultTransport is the Transport used by CreatePipe/OpenPipeReader/
+// OpenPipeWriter on Windows.
+var defaultTransport Transport = &windowsTransport{}
+
+// windowsTransport implements Transport on top of a Windows named pipe
+// (`\\.\pipe\<name>`), created and connected via go-winio so reads and
+// writes use overlapped I/O and are cancellable.
+//
+// A pipePath behaves like a FIFO: the side that calls Create acts as the
+// named pipe server, and the first OpenReader/OpenWriter call on either
+// side accepts (or dials) the single connection that both ends then share.
+type windowsTransport struct {
+	mu        sync.Mutex
+	listeners map[string]*listenerState
+}
+
+type listenerState struct {
+	listener net.Listener
+	conn     net.Conn
+	connErr  error
+	once     sync.Once
+}
+
+func pipeName(path string) string {
+	return `\\.\pipe\` + path
+}
+
+func (t *windowsTransport) Create(path string) error {
+	l, err := winio.ListenPipe(pipeName(path), &winio.PipeConfig{})
+	if err != nil {
+		return fmt.Errorf("ipc: creating named pipe %q: %w", path, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.listeners == nil {
+		t.listeners = make(map[string]*listenerState)
+	}
+	t.listeners[path] = &listenerState{listener: l}
+
+	return nil
+}
+
+// conn returns the single shared connection for path, accepting it on the
+// server side (if Create was called for this path) or dialing it as a
+// client otherwise. Both OpenReader and OpenWriter funnel through this so
+// the same duplex handle backs both directions, matching FIFO semantics.
+// The client side caches its dialed conn per path exactly like the server
+// side caches its accepted conn, so a client that calls OpenReader and
+// OpenWriter (or polls WaitForPeer) against the same path only ever dials
+// once and the two calls share that one connection.
+func (t *windowsTransport) conn(ctx context.Context, path string) (io.ReadWriteCloser, error) {
+	t.mu.Lock()
+	state, ok := t.listeners[path]
+	if !ok {
+		if t.listeners == nil {
+			t.listeners = make(map[string]*listenerState)
+		}
+		state = &listenerState{}
+		t.listeners[path] = state
+	}
+	t.mu.Unlock()
+
+	state.once.Do(func() {
+		if state.listener != nil {
+			state.conn, state.connErr = state.listener.Accept()
+		} else {
+			state.conn, state.connErr = winio.DialPipeContext(ctx, pipeName(path))
+		}
+	})
+
+	if state.connErr != nil {
+		verb := "accepting"
+		if state.listener == nil {
+			verb = "dialing"
+		}
+		return nil, fmt.Errorf("ipc: %s named pipe %q: %w", verb, path, state.connErr)
+	}
+	return state.conn, nil
+}
+
+func (t *windowsTransport) OpenReader(path string) (io.ReadCloser, error) {
+	return t.conn(context.Background(), path)
+}
+
+func (t *windowsTransport) OpenWriter(path string) (io.WriteCloser, error) {
+	return t.conn(context.Background(), path)
+}
+
+// OpenReaderContext is OpenReader, but cancellable. On the client side this
+// threads ctx through to the underlying winio.DialPipeContext dial, which
+// aborts cleanly on cancellation. On the server side the connection is
+// accepted via net.Listener.Accept, which go-winio doesn't expose a
+// cancellable variant of; a cancelled ctx there still causes this call to
+// return ctx.Err() promptly, but (as with a plain Accept call) the
+// goroutine blocked inside conn's once.Do is left running until a peer
+// actually connects.
+func (t *windowsTransport) OpenReaderContext(ctx context.Context, path string) (io.ReadCloser, error) {
+	type result struct {
+		conn io.ReadWriteCloser
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		conn, err := t.conn(ctx, path)
+		done <- result{conn, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.conn, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WaitForPeer blocks until the named pipe's single connection is accepted
+// or dialed, reusing the same shared-connection logic as OpenReader and
+// OpenWriter.
+func (t *windowsTransport) WaitForPeer(ctx context.Context, path string) error {
+	_, err := t.conn(ctx, path)
+	return err
+}
@@ -0,0 +1,90 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Writer is a cancellable, error-propagating FIFO writer returned by
+// NewWriter. Unlike OpenPipeWriter, which logs write errors to glog from a
+// background goroutine, Send returns the error to the caller.
+type Writer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	file   io.WriteCloser
+	writer *bufio.Writer
+}
+
+// NewWriter opens pipePath for writing using the platform default Transport
+// and returns a Writer. Cancelling ctx while NewWriter is waiting for a
+// peer makes it return ctx.Err(); cancelling it later unblocks a pending
+// Send and makes it return ctx.Err().
+func NewWriter(ctx context.Context, pipePath string) (*Writer, error) {
+	if !doesFileExist(pipePath) {
+		return nil, fmt.Errorf("ipc: pipe %q does not exist", pipePath)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Wait for a peer via the cancellable, non-blocking-probe path before
+	// doing the real (uninterruptible) OpenWriter, so a cancelled ctx
+	// actually aborts the open instead of leaking a goroutine blocked in
+	// it forever.
+	if err := defaultTransport.WaitForPeer(ctx, pipePath); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	file, err := defaultTransport.OpenWriter(pipePath)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("ipc: opening %q for writing: %w", pipePath, err)
+	}
+
+	w := &Writer{ctx: ctx, cancel: cancel, file: file, writer: bufio.NewWriter(file)}
+
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	return w, nil
+}
+
+// Send writes data to the pipe as a single length-prefixed frame, blocking
+// until it is flushed, ctx passed to NewWriter is cancelled, or the peer
+// disconnects (a write error, typically EPIPE).
+func (w *Writer) Send(data []byte) error {
+	var sizeBytes [8]byte
+	binary.LittleEndian.PutUint64(sizeBytes[:], uint64(len(data)))
+
+	if _, err := w.writer.Write(sizeBytes[:]); err != nil {
+		return w.translateErr(err)
+	}
+	if _, err := w.writer.Write(data); err != nil {
+		return w.translateErr(err)
+	}
+	if err := w.writer.Flush(); err != nil {
+		return w.translateErr(err)
+	}
+
+	return nil
+}
+
+// translateErr reports ctx.Err() instead of the underlying "use of closed
+// file" error when a pending write was interrupted by cancellation.
+func (w *Writer) translateErr(err error) error {
+	if err != nil && w.ctx.Err() != nil {
+		return w.ctx.Err()
+	}
+	return err
+}
+
+// Close cancels any pending Send and releases the underlying pipe.
+func (w *Writer) Close() error {
+	w.cancel()
+	return w.file.Close()
+}
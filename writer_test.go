@@ -0,0 +1,82 @@
+package ipc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewWriterCancelBeforePeer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p")
+	if err := CreatePipe(path); err != nil {
+		t.Fatalf("CreatePipe: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := NewWriter(ctx, path)
+		errCh <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("NewWriter: got err %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("NewWriter did not return after ctx was cancelled")
+	}
+}
+
+func TestNewWriterConnectsToPeer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p")
+	if err := CreatePipe(path); err != nil {
+		t.Fatalf("CreatePipe: %v", err)
+	}
+
+	ctx := context.Background()
+
+	type result struct {
+		w   *Writer
+		err error
+	}
+	writerCh := make(chan result, 1)
+	go func() {
+		w, err := NewWriter(ctx, path)
+		writerCh <- result{w, err}
+	}()
+
+	r, err := NewReader(ctx, path)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer r.Close()
+
+	var res result
+	select {
+	case res = <-writerCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("NewWriter did not connect to its peer")
+	}
+	if res.err != nil {
+		t.Fatalf("NewWriter: %v", res.err)
+	}
+	defer res.w.Close()
+
+	if err := res.w.Send([]byte("hi")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	got, err := r.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if string(got) != "hi" {
+		t.Errorf("Recv: got %q, want %q", got, "hi")
+	}
+}